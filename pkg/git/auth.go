@@ -0,0 +1,100 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	"github.com/argoproj-labs/argocd-autopilot/pkg/log"
+)
+
+type (
+	// Auth holds the authentication info needed to talk to a git remote
+	Auth struct {
+		// Method selects which authentication mechanism to use. One of
+		// AuthMethodHTTPBasic, AuthMethodSSHKey, AuthMethodSSHAgent or
+		// AuthMethodAnonymous. If empty, it is inferred from the other fields.
+		Method                  string
+		Username                string
+		Password                string
+		SSHUser                 string
+		SSHAgent                bool
+		SSHPrivateKeyPath       string
+		SSHPrivateKeyPassphrase string
+	}
+)
+
+// Authentication methods
+const (
+	AuthMethodHTTPBasic = "http-basic"
+	AuthMethodSSHKey    = "ssh-key"
+	AuthMethodSSHAgent  = "ssh-agent"
+	AuthMethodAnonymous = "anonymous"
+
+	defaultSSHUser = "git"
+)
+
+// inferMethod fills in Method, when left empty, based on which of the other
+// fields were set, so existing --git-token/--git-user usage keeps working
+// unchanged.
+func (a *Auth) inferMethod() {
+	if a.Method != "" {
+		return
+	}
+
+	switch {
+	case a.SSHAgent:
+		a.Method = AuthMethodSSHAgent
+	case a.SSHPrivateKeyPath != "":
+		a.Method = AuthMethodSSHKey
+	case a.Password != "":
+		a.Method = AuthMethodHTTPBasic
+	default:
+		a.Method = AuthMethodAnonymous
+	}
+}
+
+func getAuth(auth Auth) transport.AuthMethod {
+	auth.inferMethod()
+
+	sshUser := auth.SSHUser
+	if sshUser == "" {
+		sshUser = defaultSSHUser
+	}
+
+	switch auth.Method {
+	case AuthMethodSSHKey:
+		pk, err := ssh.NewPublicKeysFromFile(sshUser, auth.SSHPrivateKeyPath, auth.SSHPrivateKeyPassphrase)
+		if err != nil {
+			log.G().WithError(err).Error("failed to load ssh private key, falling back to anonymous auth")
+			return nil
+		}
+
+		return pk
+	case AuthMethodSSHAgent:
+		am, err := ssh.NewSSHAgentAuth(sshUser)
+		if err != nil {
+			log.G().WithError(err).Error("failed to set up ssh-agent auth, falling back to anonymous auth")
+			return nil
+		}
+
+		return am
+	case AuthMethodAnonymous:
+		return nil
+	default:
+		if auth.Password == "" {
+			return nil
+		}
+
+		return &http.BasicAuth{
+			Username: auth.Username,
+			Password: auth.Password,
+		}
+	}
+}
+
+func validAuthMethods() string {
+	return fmt.Sprintf("%s|%s|%s|%s", AuthMethodHTTPBasic, AuthMethodSSHKey, AuthMethodSSHAgent, AuthMethodAnonymous)
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func TestAuth_inferMethod(t *testing.T) {
+	tests := map[string]struct {
+		auth Auth
+		want string
+	}{
+		"explicit method wins":  {auth: Auth{Method: AuthMethodAnonymous, Password: "tok"}, want: AuthMethodAnonymous},
+		"ssh-agent":             {auth: Auth{SSHAgent: true}, want: AuthMethodSSHAgent},
+		"ssh-key":               {auth: Auth{SSHPrivateKeyPath: "/tmp/id_rsa"}, want: AuthMethodSSHKey},
+		"password implies http": {auth: Auth{Password: "tok"}, want: AuthMethodHTTPBasic},
+		"nothing set":           {auth: Auth{}, want: AuthMethodAnonymous},
+		"ssh-agent beats key":   {auth: Auth{SSHAgent: true, SSHPrivateKeyPath: "/tmp/id_rsa"}, want: AuthMethodSSHAgent},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := tt.auth
+			a.inferMethod()
+			if a.Method != tt.want {
+				t.Errorf("inferMethod() = %q, want %q", a.Method, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAuth(t *testing.T) {
+	t.Run("http-basic", func(t *testing.T) {
+		res := getAuth(Auth{Method: AuthMethodHTTPBasic, Username: "user", Password: "tok"})
+		basic, ok := res.(*http.BasicAuth)
+		if !ok {
+			t.Fatalf("getAuth() returned %T, want *http.BasicAuth", res)
+		}
+
+		if basic.Username != "user" || basic.Password != "tok" {
+			t.Errorf("getAuth() = %+v, want Username=user Password=tok", basic)
+		}
+	})
+
+	t.Run("http-basic with no password returns nil", func(t *testing.T) {
+		if res := getAuth(Auth{Method: AuthMethodHTTPBasic}); res != nil {
+			t.Errorf("getAuth() = %v, want nil", res)
+		}
+	})
+
+	t.Run("anonymous returns nil", func(t *testing.T) {
+		if res := getAuth(Auth{Method: AuthMethodAnonymous, Password: "tok"}); res != nil {
+			t.Errorf("getAuth() = %v, want nil", res)
+		}
+	})
+
+	t.Run("ssh-key with missing file falls back to anonymous", func(t *testing.T) {
+		res := getAuth(Auth{Method: AuthMethodSSHKey, SSHPrivateKeyPath: "/does/not/exist"})
+		if res != nil {
+			t.Errorf("getAuth() = %v, want nil for an unreadable key file", res)
+		}
+	})
+}
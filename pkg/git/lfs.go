@@ -0,0 +1,70 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/argoproj-labs/argocd-autopilot/pkg/log"
+)
+
+var (
+	lfsBinaryOnce sync.Once
+	lfsBinaryErr  error
+)
+
+// checkLFSBinaries verifies that both `git` and `git-lfs` are on $PATH. The
+// lookup only runs once per process, since the result can't change mid-run.
+func checkLFSBinaries() error {
+	lfsBinaryOnce.Do(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			lfsBinaryErr = fmt.Errorf("--lfs requires the 'git' binary, but it was not found on $PATH: %w", err)
+			return
+		}
+
+		if _, err := exec.LookPath("git-lfs"); err != nil {
+			lfsBinaryErr = fmt.Errorf("--lfs requires the 'git-lfs' binary, but it was not found on $PATH: %w", err)
+		}
+	})
+
+	return lfsBinaryErr
+}
+
+func runGitCmd(ctx context.Context, root string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", root}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("'git %s' failed: %w: %s", strings.Join(args, " "), err, string(out))
+	}
+
+	return nil
+}
+
+// lfsFetchAndCheckout downloads the real contents of any lfs-tracked files in
+// root, replacing the pointer files go-git left behind after a normal clone.
+func lfsFetchAndCheckout(ctx context.Context, root string) error {
+	log.G(ctx).Debug("fetching git-lfs objects")
+
+	if err := runGitCmd(ctx, root, "lfs", "fetch", "--all"); err != nil {
+		return fmt.Errorf("failed fetching git-lfs objects: %w", err)
+	}
+
+	if err := runGitCmd(ctx, root, "lfs", "checkout"); err != nil {
+		return fmt.Errorf("failed checking out git-lfs objects: %w", err)
+	}
+
+	return nil
+}
+
+// lfsPush pushes lfs objects for branch, since go-git's PushContext only
+// pushes normal git objects and would otherwise silently drop them.
+func lfsPush(ctx context.Context, root, remote, branch string) error {
+	log.G(ctx).Debug("pushing git-lfs objects")
+
+	if err := runGitCmd(ctx, root, "lfs", "push", remote, branch); err != nil {
+		return fmt.Errorf("failed pushing git-lfs objects: %w", err)
+	}
+
+	return nil
+}
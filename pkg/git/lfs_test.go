@@ -0,0 +1,95 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// withFakePath points $PATH at a temp dir containing only the given no-op
+// binaries, so checkLFSBinaries can be exercised without depending on what's
+// actually installed on the machine running the tests.
+func withFakePath(t *testing.T, binaries ...string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range binaries {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatalf("failed writing fake '%s' binary: %v", name, err)
+		}
+	}
+
+	t.Setenv("PATH", dir)
+}
+
+func resetLFSBinaryCheck() {
+	lfsBinaryOnce = sync.Once{}
+	lfsBinaryErr = nil
+}
+
+func TestCheckLFSBinaries(t *testing.T) {
+	t.Run("git missing", func(t *testing.T) {
+		resetLFSBinaryCheck()
+		withFakePath(t)
+
+		err := checkLFSBinaries()
+		if err == nil || !strings.Contains(err.Error(), "'git' binary") {
+			t.Fatalf("checkLFSBinaries() error = %v, want a 'git' binary not found error", err)
+		}
+	})
+
+	t.Run("git-lfs missing", func(t *testing.T) {
+		resetLFSBinaryCheck()
+		withFakePath(t, "git")
+
+		err := checkLFSBinaries()
+		if err == nil || !strings.Contains(err.Error(), "'git-lfs' binary") {
+			t.Fatalf("checkLFSBinaries() error = %v, want a 'git-lfs' binary not found error", err)
+		}
+	})
+
+	t.Run("both present", func(t *testing.T) {
+		resetLFSBinaryCheck()
+		withFakePath(t, "git", "git-lfs")
+
+		if err := checkLFSBinaries(); err != nil {
+			t.Fatalf("checkLFSBinaries() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("result is cached for the rest of the process", func(t *testing.T) {
+		resetLFSBinaryCheck()
+		withFakePath(t)
+		first := checkLFSBinaries()
+
+		withFakePath(t, "git", "git-lfs")
+		second := checkLFSBinaries()
+
+		if second == nil || first == nil || second.Error() != first.Error() {
+			t.Fatalf("checkLFSBinaries() = %v, want the cached result %v", second, first)
+		}
+	})
+}
+
+func TestRunGitCmd(t *testing.T) {
+	t.Run("failing subcommand is wrapped with its output", func(t *testing.T) {
+		err := runGitCmd(context.Background(), t.TempDir(), "not-a-real-subcommand")
+		if err == nil {
+			t.Fatal("runGitCmd() expected an error for an unknown subcommand")
+		}
+
+		if !strings.Contains(err.Error(), "'git not-a-real-subcommand' failed") {
+			t.Errorf("runGitCmd() error = %v, want it to name the failing command", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		if err := runGitCmd(context.Background(), t.TempDir(), "init"); err != nil {
+			t.Fatalf("runGitCmd() error = %v", err)
+		}
+	})
+}
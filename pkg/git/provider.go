@@ -0,0 +1,178 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/argoproj-labs/argocd-autopilot/pkg/log"
+)
+
+type (
+	// CreateRepoOptions holds the options for Provider.CreateRepository
+	CreateRepoOptions struct {
+		Owner   string
+		Name    string
+		Private bool
+	}
+
+	// CreatePROptions holds the options for Provider.CreatePullRequest
+	CreatePROptions struct {
+		Owner        string
+		Repo         string
+		SourceBranch string
+		TargetBranch string
+		Title        string
+		Body         string
+		Draft        bool
+	}
+
+	// ProviderOptions are passed to a ProviderFactory to construct a Provider.
+	// Host is the API base to talk to, which is the clone URL's host unless
+	// overridden by --provider-api-url.
+	ProviderOptions struct {
+		Type string
+		Auth *Auth
+		Host string
+	}
+
+	// ProviderFactory constructs a Provider from the given options
+	ProviderFactory func(opts *ProviderOptions) (Provider, error)
+
+	// ProviderMatcher reports whether host is served by this provider, based
+	// purely on the hostname - no network access.
+	ProviderMatcher func(host string) bool
+
+	// Provider is implemented by each supported git hosting provider
+	Provider interface {
+		// CreateRepository creates a new repository and returns its clone url
+		CreateRepository(ctx context.Context, opts *CreateRepoOptions) (string, error)
+		// CreatePullRequest opens a pull request and returns its url
+		CreatePullRequest(ctx context.Context, opts *CreatePROptions) (string, error)
+	}
+
+	// OrgCreator is implemented by providers that can create a missing
+	// organization/group on demand, behind --create-org.
+	OrgCreator interface {
+		EnsureOrganization(ctx context.Context, name string) error
+	}
+
+	providerRegistration struct {
+		name      string
+		matcher   ProviderMatcher
+		probePath string
+		factory   ProviderFactory
+	}
+)
+
+var providerRegistry []*providerRegistration
+
+// RegisterProvider registers a provider under name, together with a matcher
+// used to recognize hosts that are obviously served by it (e.g. by suffix)
+// and a probePath used to confirm self-hosted installs when the matcher
+// doesn't apply (e.g. "/api/v4/version" for GitLab). probePath may be empty
+// if the provider can't be auto-detected this way. Provider packages call
+// this from an init() function.
+func RegisterProvider(name string, matcher ProviderMatcher, probePath string, factory ProviderFactory) {
+	providerRegistry = append(providerRegistry, &providerRegistration{
+		name:      name,
+		matcher:   matcher,
+		probePath: probePath,
+		factory:   factory,
+	})
+}
+
+// Providers returns the names of all registered git providers, sorted
+// alphabetically.
+func Providers() []string {
+	names := make([]string, 0, len(providerRegistry))
+	for _, reg := range providerRegistry {
+		names = append(names, reg.name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func newProvider(opts *ProviderOptions) (Provider, error) {
+	for _, reg := range providerRegistry {
+		if reg.name == opts.Type {
+			return reg.factory(opts)
+		}
+	}
+
+	return nil, fmt.Errorf("unknown git provider '%s', must be one of: %s", opts.Type, strings.Join(Providers(), "|"))
+}
+
+func splitOwnerRepo(orgRepo string) (owner, name string, err error) {
+	s := strings.Split(orgRepo, "/")
+	if len(s) < 2 {
+		return "", "", fmt.Errorf("failed parsing organization and repo from '%s'", orgRepo)
+	}
+
+	return strings.Join(s[:len(s)-1], "/"), s[len(s)-1], nil
+}
+
+// detectProviderType returns explicit if set. Otherwise it first checks each
+// registered provider's matcher against hostname, and - if none matched -
+// probes apiURL (or host, if apiURL is empty) with each provider's
+// probePath, picking the first one that responds successfully. As a last
+// resort it falls back to stripping ".com" from hostname, to keep
+// github.com/gitlab.com style urls working when a probe can't run. hostname
+// is the bare host (see parseGitURL) - callers must not pass it through
+// url.Parse again, since that fails for the scp-like ssh host format.
+func detectProviderType(ctx context.Context, explicit, hostname, host, apiURL string) string {
+	if explicit != "" {
+		return explicit
+	}
+
+	for _, reg := range providerRegistry {
+		if reg.matcher != nil && reg.matcher(hostname) {
+			return reg.name
+		}
+	}
+
+	probeBase := apiURL
+	if probeBase == "" {
+		probeBase = host
+	}
+
+	for _, reg := range providerRegistry {
+		if reg.probePath == "" {
+			continue
+		}
+
+		if probeProviderAPI(ctx, probeBase, reg.probePath) {
+			return reg.name
+		}
+	}
+
+	providerType := strings.TrimSuffix(hostname, ".com")
+	log.G(ctx).Warnf("failed to detect git provider for '%s', assuming '%s' - use --provider to override", host, providerType)
+	return providerType
+}
+
+var httpProbeClient = &http.Client{Timeout: 5 * time.Second}
+
+func probeProviderAPI(ctx context.Context, base, path string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(base, "/")+path, nil)
+	if err != nil {
+		return false
+	}
+
+	res, err := httpProbeClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+
+	// A 4xx/5xx on this specific path (e.g. a plain 404 from a host that
+	// doesn't run this provider at all) is not a match - only accept a
+	// successful response as confirmation. Without this, an unrelated
+	// provider's probePath checked earlier in the registry can "match" any
+	// self-hosted host that happens to return a non-500 status for it.
+	return res.StatusCode >= 200 && res.StatusCode < 300
+}
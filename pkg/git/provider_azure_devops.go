@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+)
+
+type azureDevopsProvider struct {
+	conn *azuredevops.Connection
+}
+
+func init() {
+	matcher := func(host string) bool {
+		return strings.Contains(host, "dev.azure.com") || strings.HasSuffix(host, "visualstudio.com")
+	}
+	RegisterProvider("azure-devops", matcher, "", newAzureDevopsProvider)
+}
+
+func newAzureDevopsProvider(opts *ProviderOptions) (Provider, error) {
+	return &azureDevopsProvider{
+		conn: azuredevops.NewPatConnection(opts.Host, opts.Auth.Password),
+	}, nil
+}
+
+func (p *azureDevopsProvider) CreateRepository(ctx context.Context, opts *CreateRepoOptions) (string, error) {
+	client, err := git.NewClient(ctx, p.conn)
+	if err != nil {
+		return "", fmt.Errorf("failed creating azure devops git client: %w", err)
+	}
+
+	repo, err := client.CreateRepository(ctx, git.CreateRepositoryArgs{
+		Project: &opts.Owner,
+		GitRepositoryToCreate: &git.GitRepositoryCreateOptions{
+			Name: &opts.Name,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed creating repository '%s/%s': %w", opts.Owner, opts.Name, err)
+	}
+
+	return *repo.RemoteUrl, nil
+}
+
+func (p *azureDevopsProvider) CreatePullRequest(ctx context.Context, opts *CreatePROptions) (string, error) {
+	client, err := git.NewClient(ctx, p.conn)
+	if err != nil {
+		return "", fmt.Errorf("failed creating azure devops git client: %w", err)
+	}
+
+	source := "refs/heads/" + opts.SourceBranch
+	target := "refs/heads/" + opts.TargetBranch
+	pr, err := client.CreatePullRequest(ctx, git.CreatePullRequestArgs{
+		Project:      &opts.Owner,
+		RepositoryId: &opts.Repo,
+		GitPullRequestToCreate: &git.GitPullRequestCreateOptions{
+			Title:         &opts.Title,
+			Description:   &opts.Body,
+			SourceRefName: &source,
+			TargetRefName: &target,
+			IsDraft:       &opts.Draft,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed creating pull request on '%s/%s': %w", opts.Owner, opts.Repo, err)
+	}
+
+	return fmt.Sprintf("%s/_git/%s/pullrequest/%d", p.conn.BaseUrl, opts.Repo, *pr.PullRequestId), nil
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// bitbucketServer talks to the Bitbucket Server (Stash) REST API directly,
+// since it predates the GitHub-flavored API that go-git providers usually
+// target and has no well maintained Go client.
+type bitbucketServer struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func init() {
+	RegisterProvider("bitbucket-server", nil, "/rest/api/1.0/application-properties", newBitbucketServerProvider)
+}
+
+func newBitbucketServerProvider(opts *ProviderOptions) (Provider, error) {
+	return &bitbucketServer{
+		baseURL: strings.TrimSuffix(opts.Host, "/"),
+		token:   opts.Auth.Password,
+		client:  http.DefaultClient,
+	}, nil
+}
+
+func (p *bitbucketServer) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("bitbucket server request to '%s' failed with status %d: %s", path, res.StatusCode, string(msg))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+func (p *bitbucketServer) CreateRepository(ctx context.Context, opts *CreateRepoOptions) (string, error) {
+	body := map[string]interface{}{
+		"name":   opts.Name,
+		"public": !opts.Private,
+	}
+
+	var res struct {
+		Links struct {
+			Clone []struct {
+				Href string `json:"href"`
+				Name string `json:"name"`
+			} `json:"clone"`
+		} `json:"links"`
+	}
+
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos", opts.Owner)
+	if err := p.do(ctx, http.MethodPost, path, body, &res); err != nil {
+		return "", fmt.Errorf("failed creating repository '%s/%s': %w", opts.Owner, opts.Name, err)
+	}
+
+	for _, l := range res.Links.Clone {
+		if l.Name == "http" {
+			return l.Href, nil
+		}
+	}
+
+	if len(res.Links.Clone) > 0 {
+		return res.Links.Clone[0].Href, nil
+	}
+
+	return "", fmt.Errorf("repository '%s/%s' was created but no clone url was returned", opts.Owner, opts.Name)
+}
+
+func (p *bitbucketServer) CreatePullRequest(ctx context.Context, opts *CreatePROptions) (string, error) {
+	body := map[string]interface{}{
+		"title":       opts.Title,
+		"description": opts.Body,
+		"fromRef": map[string]interface{}{
+			"id": "refs/heads/" + opts.SourceBranch,
+		},
+		"toRef": map[string]interface{}{
+			"id": "refs/heads/" + opts.TargetBranch,
+		},
+	}
+
+	var res struct {
+		Links struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	}
+
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests", opts.Owner, opts.Repo)
+	if err := p.do(ctx, http.MethodPost, path, body, &res); err != nil {
+		return "", fmt.Errorf("failed creating pull request on '%s/%s': %w", opts.Owner, opts.Repo, err)
+	}
+
+	if len(res.Links.Self) == 0 {
+		return "", fmt.Errorf("pull request on '%s/%s' was created but no url was returned", opts.Owner, opts.Repo)
+	}
+
+	return res.Links.Self[0].Href, nil
+}
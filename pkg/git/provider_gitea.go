@@ -0,0 +1,66 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	gitea "code.gitea.io/sdk/gitea"
+)
+
+type giteaProvider struct {
+	client *gitea.Client
+}
+
+func init() {
+	RegisterProvider("gitea", nil, "/api/v1/version", newGiteaProvider)
+}
+
+func newGiteaProvider(opts *ProviderOptions) (Provider, error) {
+	client, err := gitea.NewClient(opts.Host, gitea.SetToken(opts.Auth.Password))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating gitea client for '%s': %w", opts.Host, err)
+	}
+
+	return &giteaProvider{client: client}, nil
+}
+
+func (p *giteaProvider) CreateRepository(ctx context.Context, opts *CreateRepoOptions) (string, error) {
+	repo, _, err := p.client.CreateOrgRepo(opts.Owner, gitea.CreateRepoOption{
+		Name:    opts.Name,
+		Private: opts.Private,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed creating repository '%s/%s': %w", opts.Owner, opts.Name, err)
+	}
+
+	return repo.CloneURL, nil
+}
+
+func (p *giteaProvider) CreatePullRequest(ctx context.Context, opts *CreatePROptions) (string, error) {
+	pr, _, err := p.client.CreatePullRequest(opts.Owner, opts.Repo, gitea.CreatePullRequestOption{
+		Title: opts.Title,
+		Body:  opts.Body,
+		Head:  opts.SourceBranch,
+		Base:  opts.TargetBranch,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed creating pull request on '%s/%s': %w", opts.Owner, opts.Repo, err)
+	}
+
+	return pr.HTMLURL, nil
+}
+
+// EnsureOrganization implements OrgCreator by creating a gitea organization
+// named name if one doesn't already exist.
+func (p *giteaProvider) EnsureOrganization(ctx context.Context, name string) error {
+	if _, _, err := p.client.GetOrg(name); err == nil {
+		return nil
+	}
+
+	_, _, err := p.client.CreateOrg(gitea.CreateOrgOption{Name: name})
+	if err != nil {
+		return fmt.Errorf("failed creating organization '%s': %w", name, err)
+	}
+
+	return nil
+}
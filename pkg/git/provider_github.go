@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v47/github"
+	"golang.org/x/oauth2"
+)
+
+type githubProvider struct {
+	client *gh.Client
+}
+
+func init() {
+	RegisterProvider("github", func(host string) bool { return host == "github.com" }, "/api/v3", newGithubProvider)
+}
+
+func newGithubProvider(opts *ProviderOptions) (Provider, error) {
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: opts.Auth.Password})
+	tc := oauth2.NewClient(ctx, ts)
+
+	if opts.Host == "" || opts.Host == "https://github.com" {
+		return &githubProvider{client: gh.NewClient(tc)}, nil
+	}
+
+	client, err := gh.NewEnterpriseClient(opts.Host, opts.Host, tc)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating github enterprise client for '%s': %w", opts.Host, err)
+	}
+
+	return &githubProvider{client: client}, nil
+}
+
+// EnsureOrganization implements OrgCreator. The GitHub API has no endpoint
+// for creating an organization - it can only be done through the web UI -
+// so this can only confirm it already exists.
+func (p *githubProvider) EnsureOrganization(ctx context.Context, name string) error {
+	if _, _, err := p.client.Organizations.Get(ctx, name); err != nil {
+		return fmt.Errorf("organization '%s' does not exist and GitHub does not support creating organizations via the API, please create it manually: %w", name, err)
+	}
+
+	return nil
+}
+
+func (p *githubProvider) CreateRepository(ctx context.Context, opts *CreateRepoOptions) (string, error) {
+	repo, _, err := p.client.Repositories.Create(ctx, opts.Owner, &gh.Repository{
+		Name:    gh.String(opts.Name),
+		Private: gh.Bool(opts.Private),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed creating repository '%s/%s': %w", opts.Owner, opts.Name, err)
+	}
+
+	return repo.GetCloneURL(), nil
+}
+
+func (p *githubProvider) CreatePullRequest(ctx context.Context, opts *CreatePROptions) (string, error) {
+	pr, _, err := p.client.PullRequests.Create(ctx, opts.Owner, opts.Repo, &gh.NewPullRequest{
+		Title: gh.String(opts.Title),
+		Body:  gh.String(opts.Body),
+		Head:  gh.String(opts.SourceBranch),
+		Base:  gh.String(opts.TargetBranch),
+		Draft: gh.Bool(opts.Draft),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed creating pull request on '%s/%s': %w", opts.Owner, opts.Repo, err)
+	}
+
+	return pr.GetHTMLURL(), nil
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	gl "github.com/xanzy/go-gitlab"
+)
+
+type gitlabProvider struct {
+	client *gl.Client
+}
+
+func init() {
+	RegisterProvider("gitlab", func(host string) bool { return host == "gitlab.com" }, "/api/v4/version", newGitlabProvider)
+}
+
+func newGitlabProvider(opts *ProviderOptions) (Provider, error) {
+	glOpts := []gl.ClientOptionFunc{}
+	if opts.Host != "" {
+		glOpts = append(glOpts, gl.WithBaseURL(opts.Host))
+	}
+
+	client, err := gl.NewClient(opts.Auth.Password, glOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating gitlab client: %w", err)
+	}
+
+	return &gitlabProvider{client: client}, nil
+}
+
+func (p *gitlabProvider) CreateRepository(ctx context.Context, opts *CreateRepoOptions) (string, error) {
+	visibility := gl.PublicVisibility
+	if opts.Private {
+		visibility = gl.PrivateVisibility
+	}
+
+	ns, _, err := p.client.Namespaces.GetNamespace(opts.Owner, gl.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed resolving gitlab namespace '%s': %w", opts.Owner, err)
+	}
+
+	project, _, err := p.client.Projects.CreateProject(&gl.CreateProjectOptions{
+		Name:        gl.String(opts.Name),
+		NamespaceID: gl.Int(ns.ID),
+		Visibility:  &visibility,
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed creating repository '%s/%s': %w", opts.Owner, opts.Name, err)
+	}
+
+	return project.HTTPURLToRepo, nil
+}
+
+func (p *gitlabProvider) CreatePullRequest(ctx context.Context, opts *CreatePROptions) (string, error) {
+	pid := fmt.Sprintf("%s/%s", opts.Owner, opts.Repo)
+	mr, _, err := p.client.MergeRequests.CreateMergeRequest(pid, &gl.CreateMergeRequestOptions{
+		Title:        gl.String(opts.Title),
+		Description:  gl.String(opts.Body),
+		SourceBranch: gl.String(opts.SourceBranch),
+		TargetBranch: gl.String(opts.TargetBranch),
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed creating merge request on '%s': %w", pid, err)
+	}
+
+	return mr.WebURL, nil
+}
+
+// EnsureOrganization implements OrgCreator by creating a top-level gitlab
+// group named name if one doesn't already exist.
+func (p *gitlabProvider) EnsureOrganization(ctx context.Context, name string) error {
+	if _, _, err := p.client.Groups.GetGroup(name, nil, gl.WithContext(ctx)); err == nil {
+		return nil
+	}
+
+	_, _, err := p.client.Groups.CreateGroup(&gl.CreateGroupOptions{
+		Name: gl.String(name),
+		Path: gl.String(name),
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed creating group '%s': %w", name, err)
+	}
+
+	return nil
+}
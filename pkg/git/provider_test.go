@@ -0,0 +1,100 @@
+package git
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProbeProviderAPI(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		want   bool
+	}{
+		"200 matches":        {status: http.StatusOK, want: true},
+		"204 matches":        {status: http.StatusNoContent, want: true},
+		"404 does not match": {status: http.StatusNotFound, want: false},
+		"401 does not match": {status: http.StatusUnauthorized, want: false},
+		"500 does not match": {status: http.StatusInternalServerError, want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			if got := probeProviderAPI(context.Background(), srv.URL, "/probe"); got != tt.want {
+				t.Errorf("probeProviderAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeProviderAPI_unreachable(t *testing.T) {
+	if probeProviderAPI(context.Background(), "http://127.0.0.1:0", "/probe") {
+		t.Error("probeProviderAPI() = true for an unreachable host, want false")
+	}
+}
+
+func TestDetectProviderType_explicitWins(t *testing.T) {
+	got := detectProviderType(context.Background(), "gitea", "gitlab.com", "https://gitlab.com", "")
+	if got != "gitea" {
+		t.Errorf("detectProviderType() = %q, want %q", got, "gitea")
+	}
+}
+
+func TestDetectProviderType_hostMatcher(t *testing.T) {
+	got := detectProviderType(context.Background(), "", "gitlab.com", "https://gitlab.com", "")
+	if got != "gitlab" {
+		t.Errorf("detectProviderType() = %q, want %q", got, "gitlab")
+	}
+}
+
+func TestDetectProviderType_probeFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/version" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	hostname := strings.TrimPrefix(srv.URL, "http://")
+	got := detectProviderType(context.Background(), "", hostname, srv.URL, "")
+	if got != "gitea" {
+		t.Errorf("detectProviderType() = %q, want %q", got, "gitea")
+	}
+}
+
+func TestDetectProviderType_scpLikeHost(t *testing.T) {
+	host, hostname, _, _, _, _, err := parseGitURL("git@gitlab.com:my-org/my-repo.git")
+	if err != nil {
+		t.Fatalf("parseGitURL() error = %v", err)
+	}
+
+	got := detectProviderType(context.Background(), "", hostname, host, "")
+	if got != "gitlab" {
+		t.Errorf("detectProviderType() = %q, want %q", got, "gitlab")
+	}
+}
+
+func TestSplitOwnerRepo(t *testing.T) {
+	owner, name, err := splitOwnerRepo("my-org/sub-group/my-repo")
+	if err != nil {
+		t.Fatalf("splitOwnerRepo() error = %v", err)
+	}
+
+	if owner != "my-org/sub-group" || name != "my-repo" {
+		t.Errorf("splitOwnerRepo() = (%q, %q), want (%q, %q)", owner, name, "my-org/sub-group", "my-repo")
+	}
+
+	if _, _, err := splitOwnerRepo("no-slash"); err == nil {
+		t.Error("splitOwnerRepo() expected an error for input with no slash")
+	}
+}
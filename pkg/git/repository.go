@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -20,10 +19,9 @@ import (
 	gg "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage"
-	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -34,8 +32,15 @@ import (
 type (
 	// Repository represents a git repository
 	Repository interface {
-		// Persist runs add, commit and push to the repository default remote
+		// Persist runs add, commit and push to the repository default remote. If
+		// opts.PullRequest is set, it instead pushes to a new source branch and
+		// opens a pull request from it into the target branch (the current
+		// branch, unless TargetBranch is set); the pull request's URL, if any,
+		// is then available from LastPullRequestURL.
 		Persist(ctx context.Context, opts *PushOptions) (string, error)
+		// LastPullRequestURL returns the URL of the pull request opened by the
+		// most recent Persist call, or "" if none was opened.
+		LastPullRequestURL() string
 		// CurrentBranch returns the name of the current branch
 		CurrentBranch() (string, error)
 	}
@@ -44,31 +49,57 @@ type (
 		FS               billy.Filesystem
 		Prefix           string
 		CreateIfNotExist bool
+		// AllowPullRequest registers the --provider/--provider-api-url flags
+		// even when CreateIfNotExist is false, for commands that don't create
+		// repositories but do call Persist with PullRequest set.
+		AllowPullRequest bool
 		Optional         bool
 	}
 
 	CloneOptions struct {
 		Provider         string
+		ProviderAPIURL   string
 		Repo             string
 		Auth             Auth
 		FS               fs.FS
 		Progress         io.Writer
 		CreateIfNotExist bool
+		CreateOrg        bool
+		LFS              bool
 		url              string
 		revision         string
 		path             string
+
+		signingMode          string
+		signingKeyPath       string
+		signingKeyPassphrase string
 	}
 
 	PushOptions struct {
 		AddGlobPattern string
 		CommitMsg      string
 		Progress       io.Writer
+		Signing        *SigningOptions
+		PullRequest    *PullRequestOptions
+	}
+
+	// PullRequestOptions tells Persist to push to a new branch and open a pull
+	// request instead of pushing straight to the tracked branch.
+	PullRequestOptions struct {
+		SourceBranch string
+		TargetBranch string
+		Title        string
+		Body         string
+		Draft        bool
 	}
 
 	repo struct {
 		gogit.Repository
-		auth     Auth
-		progress io.Writer
+		auth      Auth
+		progress  io.Writer
+		fs        billy.Filesystem
+		cloneOpts *CloneOptions
+		lastPRURL string
 	}
 )
 
@@ -132,18 +163,43 @@ func AddFlags(cmd *cobra.Command, opts *AddFlagsOptions) *CloneOptions {
 	cmd.PersistentFlags().StringVar(&co.Auth.Password, opts.Prefix+"git-token", "", fmt.Sprintf("Your git provider api token [%sGIT_TOKEN]", envPrefix))
 	cmd.PersistentFlags().StringVar(&co.Auth.Username, opts.Prefix+"git-user", "", fmt.Sprintf("Your git provider user name [%sGIT_USER] (not required in GitHub)", envPrefix))
 	cmd.PersistentFlags().StringVar(&co.Repo, opts.Prefix+"repo", "", fmt.Sprintf("Repository URL [%sGIT_REPO]", envPrefix))
+	cmd.PersistentFlags().StringVar(&co.Auth.Method, opts.Prefix+"auth-method", "", fmt.Sprintf("Git authentication method, one of: %s [%sAUTH_METHOD] (inferred from other flags if not set)", validAuthMethods(), envPrefix))
+	cmd.PersistentFlags().StringVar(&co.Auth.SSHPrivateKeyPath, opts.Prefix+"ssh-key", "", fmt.Sprintf("Path to an ssh private key to use for git operations [%sSSH_KEY]", envPrefix))
+	cmd.PersistentFlags().StringVar(&co.Auth.SSHPrivateKeyPassphrase, opts.Prefix+"ssh-key-passphrase", "", fmt.Sprintf("Passphrase for the ssh private key, if it is encrypted [%sSSH_KEY_PASSPHRASE]", envPrefix))
+	cmd.PersistentFlags().StringVar(&co.Auth.SSHUser, opts.Prefix+"ssh-user", "", fmt.Sprintf("The user to connect as over ssh [%sSSH_USER] (default \"%s\")", envPrefix, defaultSSHUser))
+	cmd.PersistentFlags().BoolVar(&co.Auth.SSHAgent, opts.Prefix+"ssh-agent", false, fmt.Sprintf("Authenticate using a running ssh-agent instead of a key file [%sSSH_AGENT]", envPrefix))
+	cmd.PersistentFlags().StringVar(&co.signingMode, opts.Prefix+"sign-commits", "", fmt.Sprintf("Sign commits created by autopilot, one of: %s|%s [%sSIGN_COMMITS]", SigningModeGPG, SigningModeSSH, envPrefix))
+	cmd.PersistentFlags().StringVar(&co.signingKeyPath, opts.Prefix+"signing-key", "", fmt.Sprintf("Path to the key used to sign commits [%sSIGNING_KEY]", envPrefix))
+	cmd.PersistentFlags().StringVar(&co.signingKeyPassphrase, opts.Prefix+"signing-key-passphrase", "", fmt.Sprintf("Passphrase for the signing key, if it is encrypted (used with gpg signing only) [%sSIGNING_KEY_PASSPHRASE]", envPrefix))
+	cmd.PersistentFlags().BoolVar(&co.LFS, opts.Prefix+"lfs", false, fmt.Sprintf("Fetch and push git-lfs objects using the git cli, in addition to the normal clone/push [%sLFS]", envPrefix))
 
 	util.Die(viper.BindEnv(opts.Prefix+"git-token", envPrefix+"GIT_TOKEN"))
 	util.Die(viper.BindEnv(opts.Prefix+"git-user", envPrefix+"GIT_USER"))
 	util.Die(viper.BindEnv(opts.Prefix+"repo", envPrefix+"GIT_REPO"))
+	util.Die(viper.BindEnv(opts.Prefix+"auth-method", envPrefix+"AUTH_METHOD"))
+	util.Die(viper.BindEnv(opts.Prefix+"ssh-key", envPrefix+"SSH_KEY"))
+	util.Die(viper.BindEnv(opts.Prefix+"ssh-key-passphrase", envPrefix+"SSH_KEY_PASSPHRASE"))
+	util.Die(viper.BindEnv(opts.Prefix+"ssh-user", envPrefix+"SSH_USER"))
+	util.Die(viper.BindEnv(opts.Prefix+"ssh-agent", envPrefix+"SSH_AGENT"))
+	util.Die(viper.BindEnv(opts.Prefix+"sign-commits", envPrefix+"SIGN_COMMITS"))
+	util.Die(viper.BindEnv(opts.Prefix+"signing-key", envPrefix+"SIGNING_KEY"))
+	util.Die(viper.BindEnv(opts.Prefix+"signing-key-passphrase", envPrefix+"SIGNING_KEY_PASSPHRASE"))
+	util.Die(viper.BindEnv(opts.Prefix+"lfs", envPrefix+"LFS"))
 
 	if opts.Prefix == "" {
 		cmd.Flag("git-token").Shorthand = "t"
 		cmd.Flag("git-user").Shorthand = "u"
 	}
 
-	if opts.CreateIfNotExist {
+	if opts.CreateIfNotExist || opts.AllowPullRequest {
 		cmd.PersistentFlags().StringVar(&co.Provider, opts.Prefix+"provider", "", fmt.Sprintf("The git provider, one of: %v", strings.Join(Providers(), "|")))
+		cmd.PersistentFlags().StringVar(&co.ProviderAPIURL, opts.Prefix+"provider-api-url", "", fmt.Sprintf("Override the git provider API url, for self-hosted installations where it differs from the repo URL's host [%sGIT_PROVIDER_API_URL]", envPrefix))
+		util.Die(viper.BindEnv(opts.Prefix+"provider-api-url", envPrefix+"GIT_PROVIDER_API_URL"))
+	}
+
+	if opts.CreateIfNotExist {
+		cmd.PersistentFlags().BoolVar(&co.CreateOrg, opts.Prefix+"create-org", false, fmt.Sprintf("Create the target organization/group if it doesn't already exist [%sCREATE_ORG]", envPrefix))
+		util.Die(viper.BindEnv(opts.Prefix+"create-org", envPrefix+"CREATE_ORG"))
 	}
 
 	if !opts.Optional {
@@ -159,14 +215,21 @@ func (o *CloneOptions) Parse() {
 		host    string
 		orgRepo string
 		suffix  string
+		err     error
 	)
 
-	host, orgRepo, o.path, o.revision, _, suffix, _ = util.ParseGitUrl(o.Repo)
+	host, _, orgRepo, o.path, o.revision, suffix, err = parseGitURL(o.Repo)
+	if err != nil {
+		log.G().WithError(err).Errorf("failed parsing git url '%s'", o.Repo)
+	}
+
 	o.url = host + orgRepo + suffix
 
 	if o.Auth.Username == "" {
 		o.Auth.Username = store.Default.GitHubUsername
 	}
+
+	o.Auth.inferMethod()
 }
 
 func (o *CloneOptions) GetRepo(ctx context.Context) (Repository, fs.FS, error) {
@@ -224,26 +287,95 @@ func (o *CloneOptions) Path() string {
 	return o.path
 }
 
+// needsOnDiskGit reports whether the repository must be backed by a real
+// on-disk .git directory (see newStorer), which only --lfs and
+// --sign-commits=ssh require, since both shell out to the native git/
+// git-lfs binaries.
+func (o *CloneOptions) needsOnDiskGit() bool {
+	return o.LFS || o.signingMode == SigningModeSSH
+}
+
+// GetSigningOptions returns the commit-signing configuration bound by
+// AddFlags, or nil if --sign-commits was not set.
+func (o *CloneOptions) GetSigningOptions() *SigningOptions {
+	if o.signingMode == "" {
+		return nil
+	}
+
+	return &SigningOptions{
+		Mode:          o.signingMode,
+		KeyPath:       o.signingKeyPath,
+		KeyPassphrase: o.signingKeyPassphrase,
+	}
+}
+
 func (r *repo) Persist(ctx context.Context, opts *PushOptions) (string, error) {
 	if opts == nil {
 		return "", ErrNilOpts
 	}
 
+	r.lastPRURL = ""
+
 	progress := opts.Progress
 	if progress == nil {
 		progress = r.progress
 	}
 
+	pushOpts := &gg.PushOptions{
+		Auth:     getAuth(r.auth),
+		Progress: progress,
+	}
+
+	targetBranch := ""
+	if opts.PullRequest != nil {
+		if opts.PullRequest.SourceBranch == "" {
+			return "", fmt.Errorf("PullRequestOptions.SourceBranch is required")
+		}
+
+		targetBranch = opts.PullRequest.TargetBranch
+		if targetBranch == "" {
+			var err error
+			targetBranch, err = r.CurrentBranch()
+			if err != nil {
+				return "", fmt.Errorf("failed resolving target branch: %w", err)
+			}
+		}
+
+		if err := r.checkoutNewBranch(opts.PullRequest.SourceBranch); err != nil {
+			return "", fmt.Errorf("failed creating source branch '%s': %w", opts.PullRequest.SourceBranch, err)
+		}
+
+		branchRef := plumbing.NewBranchReferenceName(opts.PullRequest.SourceBranch)
+		pushOpts.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", branchRef, branchRef))}
+	}
+
 	h, err := r.commit(opts)
 	if err != nil {
 		return "", err
 	}
 
+	if r.cloneOpts != nil && r.cloneOpts.LFS {
+		if err := checkLFSBinaries(); err != nil {
+			return "", err
+		}
+
+		pushBranch := targetBranch
+		if opts.PullRequest != nil {
+			pushBranch = opts.PullRequest.SourceBranch
+		} else if pushBranch == "" {
+			pushBranch, err = r.CurrentBranch()
+			if err != nil {
+				return "", fmt.Errorf("failed resolving branch for git-lfs push: %w", err)
+			}
+		}
+
+		if err := lfsPush(ctx, r.fs.Root(), "origin", pushBranch); err != nil {
+			return "", err
+		}
+	}
+
 	for try := 0; try < pushRetries; try++ {
-		err = r.PushContext(ctx, &gg.PushOptions{
-			Auth:     getAuth(r.auth),
-			Progress: progress,
-		})
+		err = r.PushContext(ctx, pushOpts)
 		if err == nil || !errors.Is(err, transport.ErrRepositoryNotFound) {
 			break
 		}
@@ -256,7 +388,73 @@ func (r *repo) Persist(ctx context.Context, opts *PushOptions) (string, error) {
 		time.Sleep(failureBackoffTime)
 	}
 
-	return h.String(), err
+	if err != nil || opts.PullRequest == nil {
+		return h.String(), err
+	}
+
+	prURL, err := r.createPullRequest(ctx, opts.PullRequest, targetBranch)
+	if err != nil {
+		return h.String(), fmt.Errorf("pushed branch '%s' but failed to create the pull request: %w", opts.PullRequest.SourceBranch, err)
+	}
+
+	r.lastPRURL = prURL
+	return h.String(), nil
+}
+
+// LastPullRequestURL returns the URL of the pull request opened by the most
+// recent Persist call, or "" if Persist wasn't called with PullRequest set
+// (or hasn't been called yet).
+func (r *repo) LastPullRequestURL() string {
+	return r.lastPRURL
+}
+
+func (r *repo) checkoutNewBranch(branch string) error {
+	w, err := worktree(r)
+	if err != nil {
+		return err
+	}
+
+	return w.Checkout(&gg.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: true,
+	})
+}
+
+func (r *repo) createPullRequest(ctx context.Context, opts *PullRequestOptions, targetBranch string) (string, error) {
+	co := r.cloneOpts
+	host, hostname, orgRepo, _, _, _, err := parseGitURL(co.Repo)
+	if err != nil {
+		return "", err
+	}
+
+	owner, name, err := splitOwnerRepo(orgRepo)
+	if err != nil {
+		return "", err
+	}
+
+	apiHost := host
+	if co.ProviderAPIURL != "" {
+		apiHost = co.ProviderAPIURL
+	}
+
+	p, err := newProvider(&ProviderOptions{
+		Type: detectProviderType(ctx, co.Provider, hostname, host, co.ProviderAPIURL),
+		Auth: &co.Auth,
+		Host: apiHost,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return p.CreatePullRequest(ctx, &CreatePROptions{
+		Owner:        owner,
+		Repo:         name,
+		SourceBranch: opts.SourceBranch,
+		TargetBranch: targetBranch,
+		Title:        opts.Title,
+		Body:         opts.Body,
+		Draft:        opts.Draft,
+	})
 }
 
 func (r *repo) CurrentBranch() (string, error) {
@@ -297,7 +495,45 @@ func (r *repo) commit(opts *PushOptions) (*plumbing.Hash, error) {
 		}
 	}
 
-	h, err = w.Commit(opts.CommitMsg, &gg.CommitOptions{All: true})
+	if opts.Signing != nil && opts.Signing.Mode == SigningModeSSH {
+		// go-git has no support for ssh-signed commits yet, so we shell out to
+		// the git CLI for this one commit and resolve the resulting hash back.
+		// This relies on r's storer being backed by a real on-disk .git (see
+		// newStorer) so that the staging we just did via w.AddGlob above is
+		// visible to the native `git commit` invocation.
+		return r.commitSSHSigned(opts, cfg)
+	}
+
+	commitOpts := &gg.CommitOptions{All: true}
+	if opts.Signing != nil && opts.Signing.Mode == SigningModeGPG {
+		entity, err := opts.Signing.gpgEntity()
+		if err != nil {
+			return nil, err
+		}
+
+		commitOpts.SignKey = entity
+	}
+
+	if opts.Signing != nil && (opts.Signing.CommitterName != "" || opts.Signing.CommitterEmail != "") {
+		signer := &object.Signature{
+			Name:  opts.Signing.CommitterName,
+			Email: opts.Signing.CommitterEmail,
+			When:  time.Now(),
+		}
+
+		if signer.Name == "" {
+			signer.Name = cfg.User.Name
+		}
+
+		if signer.Email == "" {
+			signer.Email = cfg.User.Email
+		}
+
+		commitOpts.Author = signer
+		commitOpts.Committer = signer
+	}
+
+	h, err = w.Commit(opts.CommitMsg, commitOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -335,7 +571,7 @@ var clone = func(ctx context.Context, opts *CloneOptions) (*repo, error) {
 	}
 
 	for try := 0; try < curPushRetries; try++ {
-		r, err = ggClone(ctx, memory.NewStorage(), opts.FS, cloneOpts)
+		r, err = ggClone(ctx, newStorer(opts.FS, opts.needsOnDiskGit()), opts.FS, cloneOpts)
 		if err == nil || !errors.Is(err, transport.ErrRepositoryNotFound) {
 			break
 		}
@@ -352,7 +588,7 @@ var clone = func(ctx context.Context, opts *CloneOptions) (*repo, error) {
 		return nil, err
 	}
 
-	repo := &repo{Repository: r, auth: opts.Auth, progress: progress}
+	repo := &repo{Repository: r, auth: opts.Auth, progress: progress, fs: opts.FS, cloneOpts: opts}
 
 	if opts.revision != "" {
 		if err := checkoutRef(repo, opts.revision); err != nil {
@@ -360,38 +596,54 @@ var clone = func(ctx context.Context, opts *CloneOptions) (*repo, error) {
 		}
 	}
 
+	if opts.LFS {
+		if err := checkLFSBinaries(); err != nil {
+			return nil, err
+		}
+
+		if err := lfsFetchAndCheckout(ctx, opts.FS.Root()); err != nil {
+			return nil, err
+		}
+	}
+
 	return repo, nil
 }
 
 var createRepo = func(ctx context.Context, opts *CloneOptions) (string, error) {
-	host, orgRepo, _, _, _, _, _ := util.ParseGitUrl(opts.Repo)
-	providerType := opts.Provider
-	if providerType == "" {
-		u, err := url.Parse(host)
-		if err != nil {
-			return "", err
-		}
+	host, hostname, orgRepo, _, _, _, err := parseGitURL(opts.Repo)
+	if err != nil {
+		return "", err
+	}
+
+	owner, name, err := splitOwnerRepo(orgRepo)
+	if err != nil {
+		return "", err
+	}
 
-		providerType = strings.TrimSuffix(u.Hostname(), ".com")
-		log.G(ctx).Warnf("--provider not specified, assuming provider from url: %s", providerType)
+	apiHost := host
+	if opts.ProviderAPIURL != "" {
+		apiHost = opts.ProviderAPIURL
 	}
 
 	p, err := newProvider(&ProviderOptions{
-		Type: providerType,
+		Type: detectProviderType(ctx, opts.Provider, hostname, host, opts.ProviderAPIURL),
 		Auth: &opts.Auth,
-		Host: host,
+		Host: apiHost,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create the repository, you can try to manually create it before trying again: %w", err)
 	}
 
-	s := strings.Split(orgRepo, "/")
-	if len(s) < 2 {
-		return "", fmt.Errorf("failed parsing organization and repo from '%s'", orgRepo)
+	if opts.CreateOrg {
+		if oc, ok := p.(OrgCreator); ok {
+			if err := oc.EnsureOrganization(ctx, owner); err != nil {
+				return "", fmt.Errorf("failed ensuring organization '%s' exists: %w", owner, err)
+			}
+		} else {
+			log.G(ctx).Warnf("--create-org is not supported by provider '%s', ignoring", opts.Provider)
+		}
 	}
 
-	owner := strings.Join(s[:len(s)-1], "/")
-	name := s[len(s)-1]
 	return p.CreateRepository(ctx, &CreateRepoOptions{
 		Owner:   owner,
 		Name:    name,
@@ -400,7 +652,7 @@ var createRepo = func(ctx context.Context, opts *CloneOptions) (string, error) {
 }
 
 var initRepo = func(ctx context.Context, opts *CloneOptions) (*repo, error) {
-	ggr, err := ggInitRepo(memory.NewStorage(), opts.FS)
+	ggr, err := ggInitRepo(newStorer(opts.FS, opts.needsOnDiskGit()), opts.FS)
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +662,7 @@ var initRepo = func(ctx context.Context, opts *CloneOptions) (*repo, error) {
 		progress = os.Stderr
 	}
 
-	r := &repo{Repository: ggr, auth: opts.Auth, progress: progress}
+	r := &repo{Repository: ggr, auth: opts.Auth, progress: progress, fs: opts.FS, cloneOpts: opts}
 	if err = r.addRemote("origin", opts.url); err != nil {
 		return nil, err
 	}
@@ -490,14 +742,3 @@ func (r *repo) initBranch(ctx context.Context, branchName string) error {
 		Create: true,
 	})
 }
-
-func getAuth(auth Auth) transport.AuthMethod {
-	if auth.Password == "" {
-		return nil
-	}
-
-	return &http.BasicAuth{
-		Username: auth.Username,
-		Password: auth.Password,
-	}
-}
@@ -0,0 +1,107 @@
+package git
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// writeTestKey generates a throwaway pgp key pair, optionally encrypting the
+// private key with passphrase, and writes it armored to a temp file.
+func writeTestKey(t *testing.T, passphrase string) string {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Test User", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed generating test pgp entity: %v", err)
+	}
+
+	if passphrase != "" {
+		if err := entity.PrivateKey.Encrypt([]byte(passphrase)); err != nil {
+			t.Fatalf("failed encrypting test private key: %v", err)
+		}
+
+		for _, subKey := range entity.Subkeys {
+			if err := subKey.PrivateKey.Encrypt([]byte(passphrase)); err != nil {
+				t.Fatalf("failed encrypting test subkey: %v", err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed creating armor encoder: %v", err)
+	}
+
+	if err := entity.SerializePrivateWithoutSigning(w, nil); err != nil {
+		t.Fatalf("failed serializing test private key: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed closing armor encoder: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.asc")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("failed writing test key: %v", err)
+	}
+
+	return path
+}
+
+func TestSigningOptions_gpgEntity(t *testing.T) {
+	t.Run("unencrypted key", func(t *testing.T) {
+		s := &SigningOptions{KeyPath: writeTestKey(t, "")}
+		entity, err := s.gpgEntity()
+		if err != nil {
+			t.Fatalf("gpgEntity() error = %v", err)
+		}
+
+		if entity.PrivateKey == nil {
+			t.Fatal("gpgEntity() returned an entity with no private key")
+		}
+	})
+
+	t.Run("encrypted key with correct passphrase", func(t *testing.T) {
+		s := &SigningOptions{KeyPath: writeTestKey(t, "hunter2"), KeyPassphrase: "hunter2"}
+		entity, err := s.gpgEntity()
+		if err != nil {
+			t.Fatalf("gpgEntity() error = %v", err)
+		}
+
+		if entity.PrivateKey.Encrypted {
+			t.Error("gpgEntity() left the private key encrypted after decrypting with the correct passphrase")
+		}
+	})
+
+	t.Run("encrypted key with wrong passphrase", func(t *testing.T) {
+		s := &SigningOptions{KeyPath: writeTestKey(t, "hunter2"), KeyPassphrase: "wrong"}
+		if _, err := s.gpgEntity(); err == nil {
+			t.Fatal("gpgEntity() expected an error for a wrong passphrase")
+		}
+	})
+
+	t.Run("missing key file", func(t *testing.T) {
+		s := &SigningOptions{KeyPath: filepath.Join(t.TempDir(), "missing.asc")}
+		if _, err := s.gpgEntity(); err == nil {
+			t.Fatal("gpgEntity() expected an error for a missing key file")
+		}
+	})
+
+	t.Run("not a pgp key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bogus.asc")
+		if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+			t.Fatalf("failed writing bogus key: %v", err)
+		}
+
+		s := &SigningOptions{KeyPath: path}
+		if _, err := s.gpgEntity(); err == nil {
+			t.Fatal("gpgEntity() expected an error for a non-pgp key file")
+		}
+	})
+}
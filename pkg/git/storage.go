@@ -0,0 +1,33 @@
+package git
+
+import (
+	"path/filepath"
+
+	billy "github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// newStorer returns the storage.Storer to clone/init worktree into. persist
+// should only be true for features that shell out to the native `git`/
+// `git-lfs` binaries against worktree's root - --lfs and --sign-commits=ssh
+// - since those need a real ".git" directory there to see the exact objects
+// and index go-git just wrote, instead of "fatal: not a git repository".
+// Everyone else keeps the previous in-memory store, so normal clones don't
+// gain an on-disk .git (and its disk footprint) they never asked for.
+func newStorer(worktree billy.Filesystem, persist bool) storage.Storer {
+	if !persist {
+		return memory.NewStorage()
+	}
+
+	root := worktree.Root()
+	if root == "" {
+		return memory.NewStorage()
+	}
+
+	dot := osfs.New(filepath.Join(root, ".git"))
+	return filesystem.NewStorage(dot, cache.NewObjectLRUDefault())
+}
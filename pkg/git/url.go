@@ -0,0 +1,53 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/argoproj-labs/argocd-autopilot/pkg/util"
+)
+
+// scpLikeURL matches git's traditional scp-like remote syntax, e.g.
+// "git@github.com:owner/repo.git". It has no URL scheme, so util.ParseGitUrl
+// (built around net/url) can't parse it on its own.
+var scpLikeURL = regexp.MustCompile(`^(?:([^@/]+)@)?([^:/]+):(.+)$`)
+
+// parseGitURL wraps util.ParseGitUrl with support for the scp-like remote
+// syntax, so that "git@host:org/repo.git" round-trips into the ssh
+// transport the same way "ssh://git@host/org/repo.git" already does.
+// Anything containing a "://" scheme is passed straight through to
+// util.ParseGitUrl unchanged.
+//
+// hostname is always a bare host (no scheme, no "user@"/":" decoration),
+// unlike host, so callers that need to match/strip a hostname - like
+// detectProviderType - don't have to re-parse the ssh-formatted host
+// themselves, which net/url can't do for the scp-like form anyway.
+func parseGitURL(repoURL string) (host, hostname, orgRepo, path, revision, suffix string, err error) {
+	m := scpLikeURL.FindStringSubmatch(repoURL)
+	if m == nil || strings.Contains(repoURL, "://") {
+		host, orgRepo, path, revision, _, suffix, err = util.ParseGitUrl(repoURL)
+		if err == nil {
+			if u, uErr := url.Parse(host); uErr == nil {
+				hostname = u.Hostname()
+			}
+		}
+
+		return
+	}
+
+	user, hostname, rest := m[1], m[2], m[3]
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	if strings.HasSuffix(rest, ".git") {
+		suffix = ".git"
+		rest = strings.TrimSuffix(rest, ".git")
+	}
+
+	orgRepo = rest
+	host = fmt.Sprintf("%s@%s:", user, hostname)
+	return host, hostname, orgRepo, "", "", suffix, nil
+}